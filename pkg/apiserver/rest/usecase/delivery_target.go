@@ -19,14 +19,49 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
 	"github.com/oam-dev/kubevela/pkg/apiserver/log"
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase/permission"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+// ImportConflictMode controls how ImportDeliveryTargets resolves a name collision with an
+// already-existing DeliveryTarget.
+type ImportConflictMode string
+
+// Supported ImportConflictMode values.
+const (
+	ImportConflictSkip      ImportConflictMode = "skip"
+	ImportConflictOverwrite ImportConflictMode = "overwrite"
+	ImportConflictFail      ImportConflictMode = "fail"
 )
 
+// ImportDeliveryTargetsOptions controls ImportDeliveryTargets behavior.
+type ImportDeliveryTargetsOptions struct {
+	Project      string
+	DryRun       bool
+	ConflictMode ImportConflictMode
+}
+
+// targetProbeInterval is how often the status reconciler re-probes every DeliveryTarget's cluster/namespace.
+const targetProbeInterval = 2 * time.Minute
+
+// targetLockTTL bounds how long an acquired target lock survives without being renewed, so a
+// crashed deployment or rollback can't wedge a target locked forever.
+const targetLockTTL = 10 * time.Minute
+
 // DeliveryTargetUsecase deliveryTarget manage api
 type DeliveryTargetUsecase interface {
 	GetDeliveryTarget(ctx context.Context, deliveryTargetName string) (*model.DeliveryTarget, error)
@@ -34,28 +69,518 @@ type DeliveryTargetUsecase interface {
 	DeleteDeliveryTarget(ctx context.Context, deliveryTargetName string) error
 	CreateDeliveryTarget(ctx context.Context, req apisv1.CreateDeliveryTargetRequest) (*apisv1.DetailDeliveryTargetResponse, error)
 	UpdateDeliveryTarget(ctx context.Context, deliveryTarget *model.DeliveryTarget, req apisv1.UpdateDeliveryTargetRequest) (*apisv1.DetailDeliveryTargetResponse, error)
-	ListDeliveryTargets(ctx context.Context, page, pageSize int, project string) (*apisv1.ListTargetResponse, error)
+	ListDeliveryTargets(ctx context.Context, page, pageSize int, project, status string) (*apisv1.ListTargetResponse, error)
+	DetailDeliveryTargetStatus(ctx context.Context, deliveryTargetName string) (*apisv1.DeliveryTargetStatusResponse, error)
+	CountAppsByTarget(ctx context.Context, targetName string) (*apisv1.TargetAppInventory, error)
+	ListAppsByTarget(ctx context.Context, targetName string, page, pageSize int) (*apisv1.ListTargetApplicationsResponse, error)
+	ImportDeliveryTargets(ctx context.Context, r io.Reader, opts ImportDeliveryTargetsOptions) (*apisv1.ImportDeliveryTargetsResponse, error)
+	ExportDeliveryTargets(ctx context.Context, project string, w io.Writer) error
+	// AcquireTargetLock locks targetName for appName's deployment/rollback at revision, so
+	// UpdateDeliveryTarget and DeleteDeliveryTarget are rejected until the lock is released.
+	AcquireTargetLock(ctx context.Context, targetName, appName, revision string) error
+	ReleaseTargetLock(ctx context.Context, targetName, appName, revision string) error
+	ListDeliveryTargetLocks(ctx context.Context, targetName string) (*apisv1.ListDeliveryTargetLocksResponse, error)
 }
 
 type deliveryTargetUsecaseImpl struct {
-	ds             datastore.DataStore
-	projectUsecase ProjectUsecase
+	ds                datastore.DataStore
+	projectUsecase    ProjectUsecase
+	templateUsecase   DeliveryTargetTemplateUsecase
+	permissionChecker permission.Checker
+	kubeClient        client.Client
 }
 
 // NewDeliveryTargetUsecase new DeliveryTarget usecase
-func NewDeliveryTargetUsecase(ds datastore.DataStore, projectUsecase ProjectUsecase) DeliveryTargetUsecase {
-	return &deliveryTargetUsecaseImpl{
-		ds:             ds,
-		projectUsecase: projectUsecase,
+func NewDeliveryTargetUsecase(ds datastore.DataStore, projectUsecase ProjectUsecase, templateUsecase DeliveryTargetTemplateUsecase, permissionChecker permission.Checker, kubeClient client.Client) DeliveryTargetUsecase {
+	dt := &deliveryTargetUsecaseImpl{
+		ds:                ds,
+		projectUsecase:    projectUsecase,
+		templateUsecase:   templateUsecase,
+		permissionChecker: permissionChecker,
+		kubeClient:        kubeClient,
+	}
+	go dt.startStatusReconciler(context.Background())
+	go dt.migrateSingleProjectTargets(context.Background())
+	return dt
+}
+
+// migrateSingleProjectTargets backfills the SharedWithProjects field on targets created before
+// cross-project sharing existed, so they keep behaving as single-project targets until an
+// operator opts them into sharing.
+func (dt *deliveryTargetUsecaseImpl) migrateSingleProjectTargets(ctx context.Context) {
+	raw, err := dt.ds.List(ctx, &model.DeliveryTarget{}, nil)
+	if err != nil {
+		log.Logger.Errorf("list delivery targets for migration failure %s", err.Error())
+		return
+	}
+	for _, r := range raw {
+		target, ok := r.(*model.DeliveryTarget)
+		if !ok || target.SharedWithProjects != nil {
+			continue
+		}
+		// Re-fetch immediately before writing and patch only SharedWithProjects, so this
+		// one-shot backfill writes over the latest copy of the record rather than the one
+		// listed above. A write that lands between this Get and the Put is still overwritten.
+		latest := &model.DeliveryTarget{Name: target.Name}
+		if err := dt.ds.Get(ctx, latest); err != nil {
+			log.Logger.Errorf("refetch delivery target %s for migration failure %s", target.Name, err.Error())
+			continue
+		}
+		if latest.SharedWithProjects != nil {
+			continue
+		}
+		latest.SharedWithProjects = []string{}
+		if err := dt.ds.Put(ctx, latest); err != nil {
+			log.Logger.Errorf("migrate delivery target %s failure %s", target.Name, err.Error())
+		}
+	}
+}
+
+// startStatusReconciler periodically probes every DeliveryTarget's cluster/namespace reachability
+// and persists the result so the UI can explain why an application can't be delivered to a target.
+func (dt *deliveryTargetUsecaseImpl) startStatusReconciler(ctx context.Context) {
+	ticker := time.NewTicker(targetProbeInterval)
+	defer ticker.Stop()
+	for {
+		dt.probeAllTargets(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (dt *deliveryTargetUsecaseImpl) probeAllTargets(ctx context.Context) {
+	raw, err := dt.ds.List(ctx, &model.DeliveryTarget{}, nil)
+	if err != nil {
+		log.Logger.Errorf("list delivery targets for probing failure %s", err.Error())
+		return
+	}
+	for _, r := range raw {
+		target, ok := r.(*model.DeliveryTarget)
+		if !ok {
+			continue
+		}
+		dt.probeTarget(ctx, target)
+	}
+}
+
+// probeTarget checks that the target's cluster API server is reachable, its namespace exists, and
+// its resource quota isn't exhausted, and records the outcome on the target's Status field.
+func (dt *deliveryTargetUsecaseImpl) probeTarget(ctx context.Context, target *model.DeliveryTarget) {
+	status := &model.DeliveryTargetStatus{
+		Phase:         model.DeliveryTargetPhaseHealthy,
+		LastProbeTime: time.Now(),
+	}
+	if target.Cluster == nil || target.Cluster.ClusterName == "" {
+		status.Phase = model.DeliveryTargetPhaseUnreachable
+		status.Message = "target has no cluster configured"
+	} else {
+		probeCtx := multicluster.ContextWithClusterName(ctx, target.Cluster.ClusterName)
+		ns := &corev1.Namespace{}
+		if err := dt.kubeClient.Get(probeCtx, client.ObjectKey{Name: target.Cluster.Namespace}, ns); err != nil {
+			status.Phase = model.DeliveryTargetPhaseUnreachable
+			status.Message = err.Error()
+		} else if ns.Status.Phase == corev1.NamespaceTerminating {
+			status.Phase = model.DeliveryTargetPhaseDegraded
+			status.Message = "namespace is terminating"
+		} else if phase, message, err := dt.probeQuota(probeCtx, target.Cluster.Namespace); err != nil {
+			status.Phase = model.DeliveryTargetPhaseDegraded
+			status.Message = fmt.Sprintf("failed to check resource quota: %s", err.Error())
+		} else if phase != "" {
+			status.Phase = phase
+			status.Message = message
+		}
+	}
+
+	// Re-fetch immediately before writing and patch only Status, so a CreateDeliveryTarget/
+	// UpdateDeliveryTarget that landed since this reconciler pass listed the record isn't
+	// reverted by round-tripping the stale copy read at the start of probeAllTargets.
+	latest := &model.DeliveryTarget{Name: target.Name}
+	if err := dt.ds.Get(ctx, latest); err != nil {
+		log.Logger.Errorf("refetch delivery target %s before status update failure %s", target.Name, err.Error())
+		return
+	}
+	latest.Status = status
+	if err := dt.ds.Put(ctx, latest); err != nil {
+		log.Logger.Errorf("update delivery target %s status failure %s", target.Name, err.Error())
+	}
+}
+
+// probeQuota reports whether any ResourceQuota in namespace has a resource fully used up. An
+// empty phase means quota isn't a concern (none exhausted, or no quotas are configured).
+func (dt *deliveryTargetUsecaseImpl) probeQuota(ctx context.Context, namespace string) (model.DeliveryTargetPhase, string, error) {
+	quotas := &corev1.ResourceQuotaList{}
+	if err := dt.kubeClient.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return "", "", err
+	}
+	for _, quota := range quotas.Items {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			if used.Cmp(hard) >= 0 {
+				return model.DeliveryTargetPhaseDegraded, fmt.Sprintf("resource quota %s exhausted for %s", quota.Name, name), nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// DetailDeliveryTargetStatus returns the last probed health status of a DeliveryTarget.
+func (dt *deliveryTargetUsecaseImpl) DetailDeliveryTargetStatus(ctx context.Context, deliveryTargetName string) (*apisv1.DeliveryTargetStatusResponse, error) {
+	target, err := dt.GetDeliveryTarget(ctx, deliveryTargetName)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrDeliveryTargetNotExist
+		}
+		return nil, err
+	}
+	if target.Status == nil {
+		return &apisv1.DeliveryTargetStatusResponse{Phase: string(model.DeliveryTargetPhaseUnreachable), Message: "target has not been probed yet"}, nil
+	}
+	return &apisv1.DeliveryTargetStatusResponse{
+		Phase:         string(target.Status.Phase),
+		Message:       target.Status.Message,
+		LastProbeTime: target.Status.LastProbeTime,
+	}, nil
+}
+
+// AcquireTargetLock locks targetName for appName's deployment/rollback at revision. Re-acquiring
+// a lock already held by the same app renews its TTL; acquiring a lock held by a different app
+// that hasn't expired fails with bcode.ErrDeliveryTargetLocked.
+func (dt *deliveryTargetUsecaseImpl) AcquireTargetLock(ctx context.Context, targetName, appName, revision string) error {
+	newLock := &model.DeliveryTargetLock{Name: targetName, Holder: appName, Revision: revision, ExpireTime: time.Now().Add(targetLockTTL)}
+	err := dt.ds.Add(ctx, newLock)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, datastore.ErrRecordExist) {
+		return err
+	}
+
+	existing := &model.DeliveryTargetLock{Name: targetName}
+	if err := dt.ds.Get(ctx, existing); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			// Raced with a concurrent release between our Add and this Get; the slot is free again.
+			return dt.AcquireTargetLock(ctx, targetName, appName, revision)
+		}
+		return err
+	}
+
+	if existing.Holder == appName {
+		// Renewing our own lock can't race with a different app acquiring it: a different
+		// app's Add would have failed against this same row, and its follow-up Get would see
+		// us as the (unexpired) holder below instead of reaching here.
+		existing.Revision = revision
+		existing.ExpireTime = time.Now().Add(targetLockTTL)
+		return dt.ds.Put(ctx, existing)
+	}
+	if time.Now().Before(existing.ExpireTime) {
+		return bcode.ErrDeliveryTargetLocked
+	}
+
+	// The lock is held by someone else but expired: reclaim it. Delete the stale row and Add a
+	// new one; if another caller reclaims it first, our Add fails and we report the conflict
+	// instead of silently overwriting their lock.
+	if err := dt.ds.Delete(ctx, existing); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return err
+	}
+	if err := dt.ds.Add(ctx, newLock); err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return bcode.ErrDeliveryTargetLocked
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleaseTargetLock releases the lock on targetName if it is still held by appName for revision.
+func (dt *deliveryTargetUsecaseImpl) ReleaseTargetLock(ctx context.Context, targetName, appName, revision string) error {
+	lock := &model.DeliveryTargetLock{Name: targetName}
+	if err := dt.ds.Get(ctx, lock); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil
+		}
+		return err
+	}
+	if lock.Holder != appName || lock.Revision != revision {
+		return nil
+	}
+	return dt.ds.Delete(ctx, lock)
+}
+
+// ListDeliveryTargetLocks returns the lock currently held on targetName, if any and unexpired.
+func (dt *deliveryTargetUsecaseImpl) ListDeliveryTargetLocks(ctx context.Context, targetName string) (*apisv1.ListDeliveryTargetLocksResponse, error) {
+	if _, err := dt.GetDeliveryTarget(ctx, targetName); err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListDeliveryTargetLocksResponse{Locks: []apisv1.DeliveryTargetLock{}}
+	lock := &model.DeliveryTargetLock{Name: targetName}
+	if err := dt.ds.Get(ctx, lock); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return resp, nil
+		}
+		return nil, err
+	}
+	if time.Now().After(lock.ExpireTime) {
+		return resp, nil
+	}
+	resp.Locks = append(resp.Locks, apisv1.DeliveryTargetLock{
+		Holder:     lock.Holder,
+		Revision:   lock.Revision,
+		ExpireTime: lock.ExpireTime,
+	})
+	return resp, nil
+}
+
+// checkTargetNotLocked rejects mutations to targetName while an unexpired lock is held.
+func (dt *deliveryTargetUsecaseImpl) checkTargetNotLocked(ctx context.Context, targetName string) error {
+	lock := &model.DeliveryTargetLock{Name: targetName}
+	err := dt.ds.Get(ctx, lock)
+	if errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if time.Now().After(lock.ExpireTime) {
+		return nil
+	}
+	return bcode.ErrDeliveryTargetLocked
+}
+
+// indexEnvBindingsByTarget lists every EnvBinding once and groups the result by target name.
+func (dt *deliveryTargetUsecaseImpl) indexEnvBindingsByTarget(ctx context.Context) (map[string][]*model.EnvBinding, error) {
+	raw, err := dt.ds.List(ctx, &model.EnvBinding{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	index := map[string][]*model.EnvBinding{}
+	for _, r := range raw {
+		binding, ok := r.(*model.EnvBinding)
+		if !ok {
+			continue
+		}
+		for _, name := range binding.TargetNames {
+			index[name] = append(index[name], binding)
+		}
+	}
+	return index, nil
+}
+
+// listEnvBindingsForTarget returns every EnvBinding whose TargetNames include targetName.
+func (dt *deliveryTargetUsecaseImpl) listEnvBindingsForTarget(ctx context.Context, targetName string) ([]*model.EnvBinding, error) {
+	index, err := dt.indexEnvBindingsByTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return index[targetName], nil
+}
+
+// countWorkflowsByStatus returns the revision counts for an app/env, broken down by run status.
+func (dt *deliveryTargetUsecaseImpl) countWorkflowsByStatus(ctx context.Context, appPrimaryKey, envName string) (total, running, failed, pending int64) {
+	raw, err := dt.ds.List(ctx, &model.Workflow{AppPrimaryKey: appPrimaryKey, EnvName: envName}, nil)
+	if err != nil {
+		log.Logger.Errorf("list workflow records for app %s failure %s", appPrimaryKey, err.Error())
+		return 0, 0, 0, 0
+	}
+	for _, r := range raw {
+		workflow, ok := r.(*model.Workflow)
+		if !ok {
+			continue
+		}
+		total++
+		switch workflow.Status {
+		case model.RevisionStatusRunning:
+			running++
+		case model.RevisionStatusFail:
+			failed++
+		default:
+			pending++
+		}
+	}
+	return total, running, failed, pending
+}
+
+// countAppsFromBindings computes inventory for a target from a pre-listed set of its bindings,
+// so a caller looping over many targets can amortize the EnvBinding scan across the whole loop.
+func (dt *deliveryTargetUsecaseImpl) countAppsFromBindings(ctx context.Context, bindings []*model.EnvBinding) *apisv1.TargetAppInventory {
+	inventory := &apisv1.TargetAppInventory{}
+	apps := map[string]struct{}{}
+	for _, binding := range bindings {
+		apps[binding.AppPrimaryKey] = struct{}{}
+		total, running, failed, pending := dt.countWorkflowsByStatus(ctx, binding.AppPrimaryKey, binding.Name)
+		inventory.RevisionNum += total
+		inventory.RunningNum += running
+		inventory.FailedNum += failed
+		inventory.PendingNum += pending
+	}
+	inventory.AppNum = int64(len(apps))
+	return inventory
+}
+
+// CountAppsByTarget computes how many applications and revisions currently deploy to the given
+// target, broken down by status.
+func (dt *deliveryTargetUsecaseImpl) CountAppsByTarget(ctx context.Context, targetName string) (*apisv1.TargetAppInventory, error) {
+	target, err := dt.GetDeliveryTarget(ctx, targetName)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := dt.listEnvBindingsForTarget(ctx, target.Name)
+	if err != nil {
+		return nil, err
+	}
+	return dt.countAppsFromBindings(ctx, bindings), nil
+}
+
+// ListAppsByTarget lists the applications currently bound to deploy to the given target.
+func (dt *deliveryTargetUsecaseImpl) ListAppsByTarget(ctx context.Context, targetName string, page, pageSize int) (*apisv1.ListTargetApplicationsResponse, error) {
+	target, err := dt.GetDeliveryTarget(ctx, targetName)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := dt.listEnvBindingsForTarget(ctx, target.Name)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListTargetApplicationsResponse{Applications: []apisv1.TargetApplication{}, Total: int64(len(bindings))}
+	for _, binding := range paginateBindings(bindings, page, pageSize) {
+		total, running, failed, pending := dt.countWorkflowsByStatus(ctx, binding.AppPrimaryKey, binding.Name)
+		resp.Applications = append(resp.Applications, apisv1.TargetApplication{
+			AppName:     binding.AppPrimaryKey,
+			EnvName:     binding.Name,
+			RevisionNum: total,
+			RunningNum:  running,
+			FailedNum:   failed,
+			PendingNum:  pending,
+		})
+	}
+	return resp, nil
+}
+
+// ImportDeliveryTargets reads a multi-document YAML/JSON manifest describing a list of
+// DeliveryTargets and creates or updates them.
+func (dt *deliveryTargetUsecaseImpl) ImportDeliveryTargets(ctx context.Context, r io.Reader, opts ImportDeliveryTargetsOptions) (*apisv1.ImportDeliveryTargetsResponse, error) {
+	if opts.ConflictMode == "" {
+		opts.ConflictMode = ImportConflictFail
+	}
+	project, err := dt.projectUsecase.GetProject(ctx, opts.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apisv1.ImportDeliveryTargetsResponse{}
+	decoder := k8syaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var manifest apisv1.DeliveryTargetManifest
+		if err := decoder.Decode(&manifest); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, bcode.ErrDeliveryTargetManifestInvalid
+		}
+		if manifest.Name == "" {
+			continue
+		}
+
+		existing := &model.DeliveryTarget{Name: manifest.Name}
+		exist, err := dt.ds.IsExist(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			switch opts.ConflictMode {
+			case ImportConflictSkip:
+				resp.Skipped = append(resp.Skipped, manifest.Name)
+				continue
+			case ImportConflictOverwrite:
+				// fall through below to update the existing record.
+			case ImportConflictFail:
+				return nil, bcode.ErrDeliveryTargetExist
+			default:
+				return nil, bcode.ErrDeliveryTargetConflictModeInvalid
+			}
+		}
+
+		variable, err := dt.templateUsecase.ValidateVariable(ctx, manifest.Template, manifest.Variable)
+		if err != nil {
+			return nil, err
+		}
+
+		target := &model.DeliveryTarget{
+			Name:        manifest.Name,
+			Alias:       manifest.Alias,
+			Description: manifest.Description,
+			Project:     project.Name,
+			Namespace:   project.Namespace,
+			Cluster:     (*model.ClusterTarget)(manifest.Cluster),
+			Variable:    variable,
+			Template:    manifest.Template,
+		}
+		if opts.DryRun {
+			resp.Planned = append(resp.Planned, manifest.Name)
+			continue
+		}
+		if exist {
+			if err := dt.ds.Put(ctx, target); err != nil {
+				return nil, err
+			}
+			resp.Updated = append(resp.Updated, manifest.Name)
+		} else {
+			if err := dt.ds.Add(ctx, target); err != nil {
+				return nil, err
+			}
+			resp.Created = append(resp.Created, manifest.Name)
+		}
+	}
+	return resp, nil
+}
+
+// ExportDeliveryTargets writes every DeliveryTarget in project as a multi-document YAML manifest,
+// the inverse of ImportDeliveryTargets.
+func (dt *deliveryTargetUsecaseImpl) ExportDeliveryTargets(ctx context.Context, project string, w io.Writer) error {
+	list, err := dt.ListDeliveryTargets(ctx, 0, 0, project, "")
+	if err != nil {
+		return err
+	}
+	for _, target := range list.Targets {
+		manifest := apisv1.DeliveryTargetManifest{
+			Name:        target.Name,
+			Alias:       target.Alias,
+			Description: target.Description,
+			Cluster:     target.Cluster,
+			Variable:    target.Variable,
+			Template:    target.Template,
+		}
+		raw, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("---\n")); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (dt *deliveryTargetUsecaseImpl) ListDeliveryTargets(ctx context.Context, page, pageSize int, project string) (*apisv1.ListTargetResponse, error) {
-	deliveryTarget := model.DeliveryTarget{}
+// ListDeliveryTargets returns the union of targets owned by the caller's projects and targets
+// shared with any of them.
+func (dt *deliveryTargetUsecaseImpl) ListDeliveryTargets(ctx context.Context, page, pageSize int, project, status string) (*apisv1.ListTargetResponse, error) {
+	callerProjects, err := dt.permissionChecker.CallerProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if project != "" {
-		deliveryTarget.Project = project
+		if !containsProject(callerProjects, project) {
+			return nil, bcode.ErrProjectIsNotMatch
+		}
+		callerProjects = []string{project}
 	}
-	deliveryTargets, err := dt.ds.List(ctx, &deliveryTarget, &datastore.ListOptions{Page: page, PageSize: pageSize, SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}}})
+
+	raw, err := dt.ds.List(ctx, &model.DeliveryTarget{}, &datastore.ListOptions{SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}}})
 	if err != nil {
 		return nil, err
 	}
@@ -63,26 +588,101 @@ func (dt *deliveryTargetUsecaseImpl) ListDeliveryTargets(ctx context.Context, pa
 	resp := &apisv1.ListTargetResponse{
 		Targets: []apisv1.DeliveryTargetBase{},
 	}
-	for _, raw := range deliveryTargets {
-		target, ok := raw.(*model.DeliveryTarget)
-		if ok {
-			resp.Targets = append(resp.Targets, *(dt.convertFromDeliveryTargetModel(ctx, target)))
+	var visible []*model.DeliveryTarget
+	for _, r := range raw {
+		target, ok := r.(*model.DeliveryTarget)
+		if !ok {
+			continue
 		}
+		if !containsProject(callerProjects, target.Project) && !containsAnyProject(target.SharedWithProjects, callerProjects) {
+			continue
+		}
+		if status != "" && (target.Status == nil || string(target.Status.Phase) != status) {
+			continue
+		}
+		visible = append(visible, target)
 	}
-	count, err := dt.ds.Count(ctx, &deliveryTarget, nil)
+	resp.Total = int64(len(visible))
+
+	visible = paginate(visible, page, pageSize)
+	bindingIndex, err := dt.indexEnvBindingsByTarget(ctx)
 	if err != nil {
 		return nil, err
 	}
-	resp.Total = count
-
+	for _, target := range visible {
+		resp.Targets = append(resp.Targets, *(dt.convertFromDeliveryTargetModel(ctx, target, bindingIndex[target.Name])))
+	}
 	return resp, nil
 }
 
+func containsProject(projects []string, project string) bool {
+	for _, p := range projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyProject(sharedWith, callerProjects []string) bool {
+	for _, p := range callerProjects {
+		if containsProject(sharedWith, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func paginate(targets []*model.DeliveryTarget, page, pageSize int) []*model.DeliveryTarget {
+	if page <= 0 || pageSize <= 0 {
+		return targets
+	}
+	start := (page - 1) * pageSize
+	if start < 0 || start >= len(targets) {
+		return nil
+	}
+	end := start + pageSize
+	if end > len(targets) {
+		end = len(targets)
+	}
+	return targets[start:end]
+}
+
+// paginateBindings applies the same page<=0||pageSize<=0 "return everything" convention as
+// paginate, but over a []*model.EnvBinding.
+func paginateBindings(bindings []*model.EnvBinding, page, pageSize int) []*model.EnvBinding {
+	if page <= 0 || pageSize <= 0 {
+		return bindings
+	}
+	start := (page - 1) * pageSize
+	if start < 0 || start >= len(bindings) {
+		return nil
+	}
+	end := start + pageSize
+	if end > len(bindings) {
+		end = len(bindings)
+	}
+	return bindings[start:end]
+}
+
 // DeleteDeliveryTarget delete application DeliveryTarget
 func (dt *deliveryTargetUsecaseImpl) DeleteDeliveryTarget(ctx context.Context, deliveryTargetName string) error {
 	deliveryTarget := &model.DeliveryTarget{
 		Name: deliveryTargetName,
 	}
+	if err := dt.ds.Get(ctx, deliveryTarget); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrDeliveryTargetNotExist
+		}
+		return err
+	}
+	if err := dt.permissionChecker.CheckProjectWritePermission(ctx, deliveryTarget.Project); err != nil {
+		return err
+	}
+	if err := dt.checkTargetNotLocked(ctx, deliveryTarget.Name); err != nil {
+		return err
+	}
+
 	if err := dt.ds.Delete(ctx, deliveryTarget); err != nil {
 		if errors.Is(err, datastore.ErrRecordNotExist) {
 			return bcode.ErrDeliveryTargetNotExist
@@ -93,7 +693,18 @@ func (dt *deliveryTargetUsecaseImpl) DeleteDeliveryTarget(ctx context.Context, d
 }
 
 func (dt *deliveryTargetUsecaseImpl) CreateDeliveryTarget(ctx context.Context, req apisv1.CreateDeliveryTargetRequest) (*apisv1.DetailDeliveryTargetResponse, error) {
+	if err := dt.permissionChecker.CheckProjectWritePermission(ctx, req.Project); err != nil {
+		return nil, err
+	}
+
 	deliveryTarget := convertCreateReqToDeliveryTargetModel(req)
+	deliveryTarget.SharedWithProjects = req.SharedWithProjects
+
+	variable, err := dt.templateUsecase.ValidateVariable(ctx, deliveryTarget.Template, deliveryTarget.Variable)
+	if err != nil {
+		return nil, err
+	}
+	deliveryTarget.Variable = variable
 
 	// check deliveryTarget name.
 	exit, err := dt.ds.IsExist(ctx, &deliveryTarget)
@@ -119,7 +730,22 @@ func (dt *deliveryTargetUsecaseImpl) CreateDeliveryTarget(ctx context.Context, r
 }
 
 func (dt *deliveryTargetUsecaseImpl) UpdateDeliveryTarget(ctx context.Context, deliveryTarget *model.DeliveryTarget, req apisv1.UpdateDeliveryTargetRequest) (*apisv1.DetailDeliveryTargetResponse, error) {
+	if err := dt.permissionChecker.CheckProjectWritePermission(ctx, deliveryTarget.Project); err != nil {
+		return nil, err
+	}
+	if err := dt.checkTargetNotLocked(ctx, deliveryTarget.Name); err != nil {
+		return nil, err
+	}
+
 	deliveryTargetModel := convertUpdateReqToDeliveryTargetModel(deliveryTarget, req)
+	deliveryTargetModel.SharedWithProjects = req.SharedWithProjects
+
+	variable, err := dt.templateUsecase.ValidateVariable(ctx, deliveryTargetModel.Template, deliveryTargetModel.Variable)
+	if err != nil {
+		return nil, err
+	}
+	deliveryTargetModel.Variable = variable
+
 	if err := dt.ds.Put(ctx, deliveryTargetModel); err != nil {
 		return nil, err
 	}
@@ -128,8 +754,15 @@ func (dt *deliveryTargetUsecaseImpl) UpdateDeliveryTarget(ctx context.Context, d
 
 // DetailDeliveryTarget detail DeliveryTarget
 func (dt *deliveryTargetUsecaseImpl) DetailDeliveryTarget(ctx context.Context, deliveryTarget *model.DeliveryTarget) (*apisv1.DetailDeliveryTargetResponse, error) {
+	if err := dt.checkTargetReadPermission(ctx, deliveryTarget); err != nil {
+		return nil, err
+	}
+	bindings, err := dt.listEnvBindingsForTarget(ctx, deliveryTarget.Name)
+	if err != nil {
+		return nil, err
+	}
 	return &apisv1.DetailDeliveryTargetResponse{
-		DeliveryTargetBase: *dt.convertFromDeliveryTargetModel(ctx, deliveryTarget),
+		DeliveryTargetBase: *dt.convertFromDeliveryTargetModel(ctx, deliveryTarget, bindings),
 	}, nil
 }
 
@@ -141,14 +774,32 @@ func (dt *deliveryTargetUsecaseImpl) GetDeliveryTarget(ctx context.Context, deli
 	if err := dt.ds.Get(ctx, deliveryTarget); err != nil {
 		return nil, err
 	}
+	if err := dt.checkTargetReadPermission(ctx, deliveryTarget); err != nil {
+		return nil, err
+	}
 	return deliveryTarget, nil
 }
 
+// checkTargetReadPermission verifies the caller can see deliveryTarget: owned by one of the
+// caller's projects, or shared with one, mirroring the visibility rule ListDeliveryTargets
+// enforces so a guessed/known target name can't bypass it.
+func (dt *deliveryTargetUsecaseImpl) checkTargetReadPermission(ctx context.Context, deliveryTarget *model.DeliveryTarget) error {
+	callerProjects, err := dt.permissionChecker.CallerProjects(ctx)
+	if err != nil {
+		return err
+	}
+	if !containsProject(callerProjects, deliveryTarget.Project) && !containsAnyProject(deliveryTarget.SharedWithProjects, callerProjects) {
+		return bcode.ErrProjectIsNotMatch
+	}
+	return nil
+}
+
 func convertUpdateReqToDeliveryTargetModel(deliveryTarget *model.DeliveryTarget, req apisv1.UpdateDeliveryTargetRequest) *model.DeliveryTarget {
 	deliveryTarget.Alias = req.Alias
 	deliveryTarget.Description = req.Description
 	deliveryTarget.Cluster = (*model.ClusterTarget)(req.Cluster)
 	deliveryTarget.Variable = req.Variable
+	deliveryTarget.Template = req.Template
 	return deliveryTarget
 }
 
@@ -159,22 +810,34 @@ func convertCreateReqToDeliveryTargetModel(req apisv1.CreateDeliveryTargetReques
 		Description: req.Description,
 		Cluster:     (*model.ClusterTarget)(req.Cluster),
 		Variable:    req.Variable,
+		Template:    req.Template,
 	}
 	return deliveryTarget
 }
 
-func (dt *deliveryTargetUsecaseImpl) convertFromDeliveryTargetModel(ctx context.Context, deliveryTarget *model.DeliveryTarget) *apisv1.DeliveryTargetBase {
-	var appNum int64 = 0
-	// TODO: query app num in target
+// convertFromDeliveryTargetModel converts deliveryTarget to its API representation. bindings is
+// deliveryTarget's pre-listed EnvBinding set (see indexEnvBindingsByTarget).
+func (dt *deliveryTargetUsecaseImpl) convertFromDeliveryTargetModel(ctx context.Context, deliveryTarget *model.DeliveryTarget, bindings []*model.EnvBinding) *apisv1.DeliveryTargetBase {
+	inventory := dt.countAppsFromBindings(ctx, bindings)
 	targetBase := &apisv1.DeliveryTargetBase{
-		Name:        deliveryTarget.Name,
-		Alias:       deliveryTarget.Alias,
-		Description: deliveryTarget.Description,
-		Cluster:     (*apisv1.ClusterTarget)(deliveryTarget.Cluster),
-		Variable:    deliveryTarget.Variable,
-		CreateTime:  deliveryTarget.CreateTime,
-		UpdateTime:  deliveryTarget.UpdateTime,
-		AppNum:      appNum,
+		Name:               deliveryTarget.Name,
+		Alias:              deliveryTarget.Alias,
+		Description:        deliveryTarget.Description,
+		Cluster:            (*apisv1.ClusterTarget)(deliveryTarget.Cluster),
+		Variable:           deliveryTarget.Variable,
+		Template:           deliveryTarget.Template,
+		SharedWithProjects: deliveryTarget.SharedWithProjects,
+		CreateTime:         deliveryTarget.CreateTime,
+		UpdateTime:         deliveryTarget.UpdateTime,
+		AppNum:             inventory.AppNum,
+		AppInventory:       inventory,
+	}
+	if deliveryTarget.Status != nil {
+		targetBase.Status = &apisv1.DeliveryTargetStatusResponse{
+			Phase:         string(deliveryTarget.Status.Phase),
+			Message:       deliveryTarget.Status.Message,
+			LastProbeTime: deliveryTarget.Status.LastProbeTime,
+		}
 	}
 
 	project, err := dt.projectUsecase.GetProject(ctx, deliveryTarget.Project)
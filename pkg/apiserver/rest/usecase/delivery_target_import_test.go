@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+)
+
+func newImportTestUsecase(ds *fakeDataStore) *deliveryTargetUsecaseImpl {
+	return &deliveryTargetUsecaseImpl{
+		ds:              ds,
+		projectUsecase:  &fakeProjectUsecase{},
+		templateUsecase: NewDeliveryTargetTemplateUsecase(ds),
+	}
+}
+
+func TestImportDeliveryTargets_DryRunChangesNothing(t *testing.T) {
+	ds := newFakeDataStore()
+	dt := newImportTestUsecase(ds)
+	ctx := context.Background()
+
+	resp, err := dt.ImportDeliveryTargets(ctx, strings.NewReader(`{"name":"t1","alias":"a1"}`), ImportDeliveryTargetsOptions{
+		Project: "team-a",
+		DryRun:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, resp.Planned)
+
+	exist, err := ds.IsExist(ctx, &model.DeliveryTarget{Name: "t1"})
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func TestImportDeliveryTargets_SkipModeLeavesExistingUntouched(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "t1", Alias: "original"}))
+	dt := newImportTestUsecase(ds)
+
+	resp, err := dt.ImportDeliveryTargets(ctx, strings.NewReader(`{"name":"t1","alias":"new"}`), ImportDeliveryTargetsOptions{
+		Project:      "team-a",
+		ConflictMode: ImportConflictSkip,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, resp.Skipped)
+
+	stored := &model.DeliveryTarget{Name: "t1"}
+	require.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, "original", stored.Alias)
+}
+
+func TestImportDeliveryTargets_FailModeRejectsConflict(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "t1", Alias: "original"}))
+	dt := newImportTestUsecase(ds)
+
+	_, err := dt.ImportDeliveryTargets(ctx, strings.NewReader(`{"name":"t1","alias":"new"}`), ImportDeliveryTargetsOptions{
+		Project:      "team-a",
+		ConflictMode: ImportConflictFail,
+	})
+	assert.Error(t, err)
+
+	stored := &model.DeliveryTarget{Name: "t1"}
+	require.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, "original", stored.Alias)
+}
+
+func TestImportDeliveryTargets_OverwriteModeUpdatesExisting(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "t1", Alias: "original"}))
+	dt := newImportTestUsecase(ds)
+
+	resp, err := dt.ImportDeliveryTargets(ctx, strings.NewReader(`{"name":"t1","alias":"new"}`), ImportDeliveryTargetsOptions{
+		Project:      "team-a",
+		ConflictMode: ImportConflictOverwrite,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, resp.Updated)
+
+	stored := &model.DeliveryTarget{Name: "t1"}
+	require.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, "new", stored.Alias)
+}
+
+func TestImportDeliveryTargets_UnknownConflictModeFailsClosed(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "t1", Alias: "original"}))
+	dt := newImportTestUsecase(ds)
+
+	_, err := dt.ImportDeliveryTargets(ctx, strings.NewReader(`{"name":"t1","alias":"new"}`), ImportDeliveryTargetsOptions{
+		Project:      "team-a",
+		ConflictMode: "bogus",
+	})
+	assert.Error(t, err)
+
+	stored := &model.DeliveryTarget{Name: "t1"}
+	require.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, "original", stored.Alias, "an unrecognized conflict mode must not silently overwrite")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+func newLockTestUsecase() *deliveryTargetUsecaseImpl {
+	return &deliveryTargetUsecaseImpl{ds: newFakeDataStore()}
+}
+
+func TestAcquireTargetLock_RejectsWhileHeldByAnotherApp(t *testing.T) {
+	dt := newLockTestUsecase()
+	ctx := context.Background()
+
+	require.NoError(t, dt.AcquireTargetLock(ctx, "target-a", "app-1", "rev-1"))
+
+	err := dt.AcquireTargetLock(ctx, "target-a", "app-2", "rev-1")
+	assert.ErrorIs(t, err, bcode.ErrDeliveryTargetLocked)
+}
+
+func TestAcquireTargetLock_SameAppRenews(t *testing.T) {
+	dt := newLockTestUsecase()
+	ctx := context.Background()
+
+	require.NoError(t, dt.AcquireTargetLock(ctx, "target-a", "app-1", "rev-1"))
+	require.NoError(t, dt.AcquireTargetLock(ctx, "target-a", "app-1", "rev-2"))
+
+	lock := &model.DeliveryTargetLock{Name: "target-a"}
+	require.NoError(t, dt.ds.Get(ctx, lock))
+	assert.Equal(t, "rev-2", lock.Revision)
+}
+
+func TestAcquireTargetLock_ReclaimsExpiredLock(t *testing.T) {
+	dt := newLockTestUsecase()
+	ctx := context.Background()
+
+	require.NoError(t, dt.ds.Add(ctx, &model.DeliveryTargetLock{
+		Name:       "target-a",
+		Holder:     "app-1",
+		Revision:   "rev-1",
+		ExpireTime: time.Now().Add(-time.Minute),
+	}))
+
+	require.NoError(t, dt.AcquireTargetLock(ctx, "target-a", "app-2", "rev-1"))
+
+	lock := &model.DeliveryTargetLock{Name: "target-a"}
+	require.NoError(t, dt.ds.Get(ctx, lock))
+	assert.Equal(t, "app-2", lock.Holder)
+}
+
+// TestAcquireTargetLock_ConcurrentAcquireOnlyOneWins is the regression test for the race the
+// previous Get-then-blind-write implementation had: two different apps racing to acquire the
+// same free target must not both believe they hold the lock.
+func TestAcquireTargetLock_ConcurrentAcquireOnlyOneWins(t *testing.T) {
+	dt := newLockTestUsecase()
+	ctx := context.Background()
+
+	const contenders = 20
+	var wg sync.WaitGroup
+	results := make([]error, contenders)
+	for i := 0; i < contenders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = dt.AcquireTargetLock(ctx, "target-a", "app-"+string(rune('A'+i)), "rev-1")
+		}(i)
+	}
+	wg.Wait()
+
+	wins := 0
+	for _, err := range results {
+		if err == nil {
+			wins++
+		}
+	}
+	assert.Equal(t, 1, wins, "exactly one concurrent acquirer should win the lock")
+
+	lock := &model.DeliveryTargetLock{Name: "target-a"}
+	require.NoError(t, dt.ds.Get(ctx, lock))
+}
+
+func TestReleaseTargetLock_OnlyCurrentHolderReleases(t *testing.T) {
+	dt := newLockTestUsecase()
+	ctx := context.Background()
+	require.NoError(t, dt.AcquireTargetLock(ctx, "target-a", "app-1", "rev-1"))
+
+	// A stale release from a superseded revision of the same app must not tear down a lock a
+	// later deployment/rollback has since acquired.
+	require.NoError(t, dt.ReleaseTargetLock(ctx, "target-a", "app-1", "rev-0"))
+	assert.ErrorIs(t, dt.checkTargetNotLocked(ctx, "target-a"), bcode.ErrDeliveryTargetLocked)
+
+	require.NoError(t, dt.ReleaseTargetLock(ctx, "target-a", "app-1", "rev-1"))
+	assert.NoError(t, dt.checkTargetNotLocked(ctx, "target-a"))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+)
+
+// fakeKubeClient implements client.Client for just the Get (Namespace) and List (ResourceQuota)
+// calls probeTarget makes. Embedding the real interface keeps it assignable even if the
+// interface grows methods this fake doesn't implement.
+type fakeKubeClient struct {
+	client.Client
+
+	namespaces map[string]*corev1.Namespace
+	quotas     map[string][]corev1.ResourceQuota
+	getErr     error
+}
+
+func (f *fakeKubeClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if f.getErr != nil {
+		return f.getErr
+	}
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "unsupported"}, key.Name)
+	}
+	stored, ok := f.namespaces[key.Name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, key.Name)
+	}
+	*ns = *stored
+	return nil
+}
+
+func (f *fakeKubeClient) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	quotaList, ok := list.(*corev1.ResourceQuotaList)
+	if !ok {
+		return nil
+	}
+	listOpts := &client.ListOptions{}
+	for _, o := range opts {
+		o.ApplyToList(listOpts)
+	}
+	namespace := ""
+	if listOpts.Namespace != "" {
+		namespace = listOpts.Namespace
+	}
+	quotaList.Items = f.quotas[namespace]
+	return nil
+}
+
+func probeTestTarget() *model.DeliveryTarget {
+	return &model.DeliveryTarget{
+		Name:    "probe-target",
+		Cluster: &model.ClusterTarget{ClusterName: "c1", Namespace: "ns1"},
+	}
+}
+
+func probeStatus(t *testing.T, ds *fakeDataStore) *model.DeliveryTargetStatus {
+	t.Helper()
+	stored := &model.DeliveryTarget{Name: "probe-target"}
+	require.NoError(t, ds.Get(context.Background(), stored))
+	require.NotNil(t, stored.Status)
+	return stored.Status
+}
+
+func TestProbeTarget_HealthyWhenNamespaceExistsAndQuotaOK(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	target := probeTestTarget()
+	require.NoError(t, ds.Add(ctx, target))
+
+	dt := &deliveryTargetUsecaseImpl{ds: ds, kubeClient: &fakeKubeClient{
+		namespaces: map[string]*corev1.Namespace{"ns1": {Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}},
+	}}
+	dt.probeTarget(ctx, target)
+
+	assert.Equal(t, model.DeliveryTargetPhaseHealthy, probeStatus(t, ds).Phase)
+}
+
+func TestProbeTarget_UnreachableWhenNamespaceGetFails(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	target := probeTestTarget()
+	require.NoError(t, ds.Add(ctx, target))
+
+	dt := &deliveryTargetUsecaseImpl{ds: ds, kubeClient: &fakeKubeClient{
+		getErr: apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "ns1"),
+	}}
+	dt.probeTarget(ctx, target)
+
+	assert.Equal(t, model.DeliveryTargetPhaseUnreachable, probeStatus(t, ds).Phase)
+}
+
+func TestProbeTarget_DegradedWhenNamespaceTerminating(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	target := probeTestTarget()
+	require.NoError(t, ds.Add(ctx, target))
+
+	dt := &deliveryTargetUsecaseImpl{ds: ds, kubeClient: &fakeKubeClient{
+		namespaces: map[string]*corev1.Namespace{"ns1": {Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}}},
+	}}
+	dt.probeTarget(ctx, target)
+
+	status := probeStatus(t, ds)
+	assert.Equal(t, model.DeliveryTargetPhaseDegraded, status.Phase)
+	assert.Contains(t, status.Message, "terminating")
+}
+
+func TestProbeTarget_DegradedWhenQuotaExhausted(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	target := probeTestTarget()
+	require.NoError(t, ds.Add(ctx, target))
+
+	dt := &deliveryTargetUsecaseImpl{ds: ds, kubeClient: &fakeKubeClient{
+		namespaces: map[string]*corev1.Namespace{"ns1": {Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}},
+		quotas: map[string][]corev1.ResourceQuota{"ns1": {{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute-quota"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			},
+		}}},
+	}}
+	dt.probeTarget(ctx, target)
+
+	status := probeStatus(t, ds)
+	assert.Equal(t, model.DeliveryTargetPhaseDegraded, status.Phase)
+	assert.Contains(t, status.Message, "quota")
+}
+
+func TestMigrateSingleProjectTargets_BackfillsOnlyNilSharedWithProjects(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "legacy"}))
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "already-shared", SharedWithProjects: []string{"team-a"}}))
+
+	dt := &deliveryTargetUsecaseImpl{ds: ds}
+	dt.migrateSingleProjectTargets(ctx)
+
+	legacy := &model.DeliveryTarget{Name: "legacy"}
+	require.NoError(t, ds.Get(ctx, legacy))
+	assert.Equal(t, []string{}, legacy.SharedWithProjects)
+
+	alreadyShared := &model.DeliveryTarget{Name: "already-shared"}
+	require.NoError(t, ds.Get(ctx, alreadyShared))
+	assert.Equal(t, []string{"team-a"}, alreadyShared.SharedWithProjects)
+}
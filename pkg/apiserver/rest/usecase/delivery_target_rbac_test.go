@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase/permission"
+)
+
+// fakeDataStore is a minimal in-memory datastore.DataStore keyed by (concrete type, Name), shared
+// by the delivery target and template tests in this package. Embedding the real interface means it stays
+// assignable to ds datastore.DataStore even if the interface grows methods this fake doesn't
+// implement; those just aren't exercised by these tests.
+type fakeDataStore struct {
+	datastore.DataStore
+
+	mu    sync.Mutex
+	items map[string]datastore.Entity
+}
+
+func newFakeDataStore() *fakeDataStore {
+	return &fakeDataStore{items: map[string]datastore.Entity{}}
+}
+
+func entityKey(entity datastore.Entity) string {
+	v := reflect.ValueOf(entity)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return fmt.Sprintf("%T/%s", entity, v.FieldByName("Name").String())
+}
+
+func cloneEntity(entity datastore.Entity) datastore.Entity {
+	v := reflect.ValueOf(entity).Elem()
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	return cp.Interface().(datastore.Entity)
+}
+
+func (f *fakeDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k := entityKey(entity)
+	if _, ok := f.items[k]; ok {
+		return datastore.ErrRecordExist
+	}
+	f.items[k] = cloneEntity(entity)
+	return nil
+}
+
+func (f *fakeDataStore) Put(_ context.Context, entity datastore.Entity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[entityKey(entity)] = cloneEntity(entity)
+	return nil
+}
+
+func (f *fakeDataStore) Get(_ context.Context, entity datastore.Entity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored, ok := f.items[entityKey(entity)]
+	if !ok {
+		return datastore.ErrRecordNotExist
+	}
+	reflect.ValueOf(entity).Elem().Set(reflect.ValueOf(stored).Elem())
+	return nil
+}
+
+func (f *fakeDataStore) Delete(_ context.Context, entity datastore.Entity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k := entityKey(entity)
+	if _, ok := f.items[k]; !ok {
+		return datastore.ErrRecordNotExist
+	}
+	delete(f.items, k)
+	return nil
+}
+
+func (f *fakeDataStore) List(_ context.Context, entity datastore.Entity, _ *datastore.ListOptions) ([]datastore.Entity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prefix := fmt.Sprintf("%T/", entity)
+	var out []datastore.Entity
+	for k, v := range f.items {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, cloneEntity(v))
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDataStore) IsExist(_ context.Context, entity datastore.Entity) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.items[entityKey(entity)]
+	return ok, nil
+}
+
+// fakePermissionChecker implements permission.Checker for just the calls this package makes.
+type fakePermissionChecker struct {
+	permission.Checker
+
+	callerProjects []string
+	writable       map[string]bool
+}
+
+func (f *fakePermissionChecker) CallerProjects(_ context.Context) ([]string, error) {
+	return f.callerProjects, nil
+}
+
+func (f *fakePermissionChecker) CheckProjectWritePermission(_ context.Context, project string) error {
+	if f.writable[project] {
+		return nil
+	}
+	return errors.New("forbidden")
+}
+
+// fakeProjectUsecase implements ProjectUsecase for just GetProject.
+type fakeProjectUsecase struct {
+	ProjectUsecase
+}
+
+func (f *fakeProjectUsecase) GetProject(_ context.Context, name string) (*model.Project, error) {
+	return &model.Project{Name: name}, nil
+}
+
+func TestListDeliveryTargets_UnionOfOwnedAndShared(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "owned", Project: "team-a"}))
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "shared-with-a", Project: "team-b", SharedWithProjects: []string{"team-a"}}))
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "other-teams-private", Project: "team-c"}))
+
+	dt := &deliveryTargetUsecaseImpl{
+		ds:                ds,
+		projectUsecase:    &fakeProjectUsecase{},
+		permissionChecker: &fakePermissionChecker{callerProjects: []string{"team-a"}},
+	}
+
+	resp, err := dt.ListDeliveryTargets(ctx, 0, 0, "", "")
+	require.NoError(t, err)
+
+	var names []string
+	for _, target := range resp.Targets {
+		names = append(names, target.Name)
+	}
+	assert.ElementsMatch(t, []string{"owned", "shared-with-a"}, names)
+}
+
+func TestDeleteDeliveryTarget_WritePermissionIsCheckedOnOwningProjectNotShared(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "shared-target", Project: "team-a", SharedWithProjects: []string{"team-b"}}))
+
+	// The caller can write to the project the target is shared with, but not the owning project.
+	dt := &deliveryTargetUsecaseImpl{
+		ds:                ds,
+		permissionChecker: &fakePermissionChecker{writable: map[string]bool{"team-b": true}},
+	}
+
+	err := dt.DeleteDeliveryTarget(ctx, "shared-target")
+	assert.Error(t, err)
+
+	exist, err := ds.IsExist(ctx, &model.DeliveryTarget{Name: "shared-target"})
+	require.NoError(t, err)
+	assert.True(t, exist, "target must not be deleted when the caller lacks write permission on the owning project")
+}
+
+func TestDeleteDeliveryTarget_AllowedWithWritePermissionOnOwningProject(t *testing.T) {
+	ds := newFakeDataStore()
+	ctx := context.Background()
+	require.NoError(t, ds.Add(ctx, &model.DeliveryTarget{Name: "owned-target", Project: "team-a"}))
+
+	dt := &deliveryTargetUsecaseImpl{
+		ds:                ds,
+		permissionChecker: &fakePermissionChecker{writable: map[string]bool{"team-a": true}},
+	}
+
+	require.NoError(t, dt.DeleteDeliveryTarget(ctx, "owned-target"))
+
+	exist, err := ds.IsExist(ctx, &model.DeliveryTarget{Name: "owned-target"})
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
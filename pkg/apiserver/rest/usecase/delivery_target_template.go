@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+// DeliveryTargetTemplateUsecase manages reusable variable schemas for DeliveryTargets.
+type DeliveryTargetTemplateUsecase interface {
+	GetDeliveryTargetTemplate(ctx context.Context, name string) (*model.DeliveryTargetTemplate, error)
+	CreateDeliveryTargetTemplate(ctx context.Context, req apisv1.CreateDeliveryTargetTemplateRequest) (*apisv1.DeliveryTargetTemplateBase, error)
+	ListDeliveryTargetTemplates(ctx context.Context) (*apisv1.ListDeliveryTargetTemplateResponse, error)
+	DeleteDeliveryTargetTemplate(ctx context.Context, name string) error
+	// ValidateVariable validates variable against templateName's JSON schema and returns it with
+	// any missing defaults filled in. An empty templateName is a no-op.
+	ValidateVariable(ctx context.Context, templateName string, variable map[string]interface{}) (map[string]interface{}, error)
+}
+
+type deliveryTargetTemplateUsecaseImpl struct {
+	ds datastore.DataStore
+}
+
+// NewDeliveryTargetTemplateUsecase new DeliveryTargetTemplate usecase
+func NewDeliveryTargetTemplateUsecase(ds datastore.DataStore) DeliveryTargetTemplateUsecase {
+	return &deliveryTargetTemplateUsecaseImpl{ds: ds}
+}
+
+func (t *deliveryTargetTemplateUsecaseImpl) GetDeliveryTargetTemplate(ctx context.Context, name string) (*model.DeliveryTargetTemplate, error) {
+	template := &model.DeliveryTargetTemplate{Name: name}
+	if err := t.ds.Get(ctx, template); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrDeliveryTargetTemplateNotExist
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (t *deliveryTargetTemplateUsecaseImpl) CreateDeliveryTargetTemplate(ctx context.Context, req apisv1.CreateDeliveryTargetTemplateRequest) (*apisv1.DeliveryTargetTemplateBase, error) {
+	template := &model.DeliveryTargetTemplate{
+		Name:           req.Name,
+		Alias:          req.Alias,
+		Description:    req.Description,
+		VariableSchema: req.VariableSchema,
+		Defaults:       req.Defaults,
+	}
+	if _, err := compileVariableSchema(template.VariableSchema); err != nil {
+		log.Logger.Errorf("compile variable schema for template %s failure %s", template.Name, err.Error())
+		return nil, bcode.ErrDeliveryTargetTemplateSchemaInvalid
+	}
+
+	exist, err := t.ds.IsExist(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, bcode.ErrDeliveryTargetTemplateExist
+	}
+	if err := t.ds.Add(ctx, template); err != nil {
+		return nil, err
+	}
+	return convertDeliveryTargetTemplateModel2Base(template), nil
+}
+
+func (t *deliveryTargetTemplateUsecaseImpl) ListDeliveryTargetTemplates(ctx context.Context) (*apisv1.ListDeliveryTargetTemplateResponse, error) {
+	raw, err := t.ds.List(ctx, &model.DeliveryTargetTemplate{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListDeliveryTargetTemplateResponse{Templates: []apisv1.DeliveryTargetTemplateBase{}}
+	for _, r := range raw {
+		template, ok := r.(*model.DeliveryTargetTemplate)
+		if !ok {
+			continue
+		}
+		resp.Templates = append(resp.Templates, *convertDeliveryTargetTemplateModel2Base(template))
+	}
+	return resp, nil
+}
+
+func (t *deliveryTargetTemplateUsecaseImpl) DeleteDeliveryTargetTemplate(ctx context.Context, name string) error {
+	if err := t.ds.Delete(ctx, &model.DeliveryTargetTemplate{Name: name}); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrDeliveryTargetTemplateNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *deliveryTargetTemplateUsecaseImpl) ValidateVariable(ctx context.Context, templateName string, variable map[string]interface{}) (map[string]interface{}, error) {
+	if templateName == "" {
+		return variable, nil
+	}
+	template, err := t.GetDeliveryTargetTemplate(ctx, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]interface{}{}
+	for k, v := range template.Defaults {
+		merged[k] = v
+	}
+	for k, v := range variable {
+		merged[k] = v
+	}
+
+	schema, err := compileVariableSchema(template.VariableSchema)
+	if err != nil {
+		log.Logger.Errorf("compile variable schema for template %s failure %s", templateName, err.Error())
+		return nil, bcode.ErrDeliveryTargetTemplateSchemaInvalid
+	}
+	if err := schema.ValidateInterface(merged); err != nil {
+		log.Logger.Errorf("validate variable against template %s failure %s", templateName, err.Error())
+		return nil, bcode.ErrDeliveryTargetVariableInvalid
+	}
+	return merged, nil
+}
+
+func compileVariableSchema(rawSchema string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("variable.json", bytes.NewReader([]byte(rawSchema))); err != nil {
+		return nil, err
+	}
+	return compiler.Compile("variable.json")
+}
+
+func convertDeliveryTargetTemplateModel2Base(template *model.DeliveryTargetTemplate) *apisv1.DeliveryTargetTemplateBase {
+	return &apisv1.DeliveryTargetTemplateBase{
+		Name:           template.Name,
+		Alias:          template.Alias,
+		Description:    template.Description,
+		VariableSchema: template.VariableSchema,
+		Defaults:       template.Defaults,
+		CreateTime:     template.CreateTime,
+	}
+}
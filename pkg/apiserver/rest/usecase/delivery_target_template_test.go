@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+const testVariableSchema = `{
+	"type": "object",
+	"properties": {"region": {"type": "string"}},
+	"required": ["region"]
+}`
+
+func TestValidateVariable_EmptyTemplateIsNoop(t *testing.T) {
+	tpl := NewDeliveryTargetTemplateUsecase(newFakeDataStore())
+
+	variable, err := tpl.ValidateVariable(context.Background(), "", map[string]interface{}{"foo": "bar"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, variable)
+}
+
+func TestValidateVariable_MergesDefaultsAndEnforcesSchema(t *testing.T) {
+	ctx := context.Background()
+	tpl := NewDeliveryTargetTemplateUsecase(newFakeDataStore())
+	_, err := tpl.CreateDeliveryTargetTemplate(ctx, apisv1.CreateDeliveryTargetTemplateRequest{
+		Name:           "bare-metal",
+		VariableSchema: testVariableSchema,
+		Defaults:       map[string]interface{}{"region": "default-region"},
+	})
+	require.NoError(t, err)
+
+	merged, err := tpl.ValidateVariable(ctx, "bare-metal", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "default-region", merged["region"])
+
+	overridden, err := tpl.ValidateVariable(ctx, "bare-metal", map[string]interface{}{"region": "us-west"})
+	require.NoError(t, err)
+	assert.Equal(t, "us-west", overridden["region"])
+}
+
+func TestValidateVariable_RejectsValueThatViolatesSchema(t *testing.T) {
+	ctx := context.Background()
+	tpl := NewDeliveryTargetTemplateUsecase(newFakeDataStore())
+	_, err := tpl.CreateDeliveryTargetTemplate(ctx, apisv1.CreateDeliveryTargetTemplateRequest{
+		Name:           "bare-metal",
+		VariableSchema: testVariableSchema,
+	})
+	require.NoError(t, err)
+
+	_, err = tpl.ValidateVariable(ctx, "bare-metal", map[string]interface{}{"region": 123})
+	assert.ErrorIs(t, err, bcode.ErrDeliveryTargetVariableInvalid)
+}
+
+func TestValidateVariable_UnknownTemplateFails(t *testing.T) {
+	tpl := NewDeliveryTargetTemplateUsecase(newFakeDataStore())
+	_, err := tpl.ValidateVariable(context.Background(), "does-not-exist", map[string]interface{}{})
+	assert.ErrorIs(t, err, bcode.ErrDeliveryTargetTemplateNotExist)
+}
+
+func TestCreateDeliveryTargetTemplate_RejectsInvalidSchema(t *testing.T) {
+	tpl := NewDeliveryTargetTemplateUsecase(newFakeDataStore())
+	_, err := tpl.CreateDeliveryTargetTemplate(context.Background(), apisv1.CreateDeliveryTargetTemplateRequest{
+		Name:           "broken",
+		VariableSchema: "{not valid json",
+	})
+	assert.ErrorIs(t, err, bcode.ErrDeliveryTargetTemplateSchemaInvalid)
+}